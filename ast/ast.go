@@ -0,0 +1,156 @@
+// Package ast exposes a parsed gval expression as a tree of nodes instead of the opaque
+// closure Language.NewEvaluable normally produces. A caller can walk the tree with a
+// Visitor to inspect it (pretty-printing, static analysis, an Ident allow-list) or rewrite
+// it (constant folding, expression transforms) before it is evaluated.
+package ast
+
+// Pos is the byte offset of a node's leading token within the original expression.
+type Pos int
+
+// Node is a node of a parsed gval expression.
+type Node interface {
+	// Pos returns the position of the node's leading token in the source expression.
+	Pos() Pos
+	// Accept first lets v rewrite the node's children, then passes the node itself to
+	// v.Visit and returns whatever Visit returns in its place.
+	Accept(v Visitor) Node
+}
+
+// Visitor visits or rewrites a Node during Accept. A Visitor that only inspects a tree
+// can implement Visit by returning n unchanged.
+type Visitor interface {
+	Visit(n Node) Node
+}
+
+// Literal is a constant value: a number, string, char or bool.
+type Literal struct {
+	PosVal Pos
+	Value  interface{}
+}
+
+func (n *Literal) Pos() Pos { return n.PosVal }
+
+func (n *Literal) Accept(v Visitor) Node { return v.Visit(n) }
+
+// Ident is a variable or constant reference, such as a parameter name or a dotted
+// selector like foo.bar.
+type Ident struct {
+	PosVal Pos
+	Name   string
+}
+
+func (n *Ident) Pos() Pos { return n.PosVal }
+
+func (n *Ident) Accept(v Visitor) Node { return v.Visit(n) }
+
+// BinaryOp is an infix operator application, such as a + b.
+type BinaryOp struct {
+	PosVal      Pos
+	Op          string
+	Left, Right Node
+}
+
+func (n *BinaryOp) Pos() Pos { return n.PosVal }
+
+func (n *BinaryOp) Accept(v Visitor) Node {
+	n.Left = n.Left.Accept(v)
+	n.Right = n.Right.Accept(v)
+	return v.Visit(n)
+}
+
+// PrefixOp is a prefix operator application, such as -a or !a.
+type PrefixOp struct {
+	PosVal  Pos
+	Op      string
+	Operand Node
+}
+
+func (n *PrefixOp) Pos() Pos { return n.PosVal }
+
+func (n *PrefixOp) Accept(v Visitor) Node {
+	n.Operand = n.Operand.Accept(v)
+	return v.Visit(n)
+}
+
+// Call is a function call, such as date("2020-01-01").
+type Call struct {
+	PosVal Pos
+	Name   string
+	Args   []Node
+}
+
+func (n *Call) Pos() Pos { return n.PosVal }
+
+func (n *Call) Accept(v Visitor) Node {
+	for i, a := range n.Args {
+		n.Args[i] = a.Accept(v)
+	}
+	return v.Visit(n)
+}
+
+// Conditional is the ternary a ? b : c operator.
+type Conditional struct {
+	PosVal                  Pos
+	CondExpr, TrueExpr, FalseExpr Node
+}
+
+func (n *Conditional) Pos() Pos { return n.PosVal }
+
+func (n *Conditional) Accept(v Visitor) Node {
+	n.CondExpr = n.CondExpr.Accept(v)
+	n.TrueExpr = n.TrueExpr.Accept(v)
+	n.FalseExpr = n.FalseExpr.Accept(v)
+	return v.Visit(n)
+}
+
+// Index is a bracketed access, such as a[b].
+type Index struct {
+	PosVal   Pos
+	Receiver Node
+	Key      Node
+}
+
+func (n *Index) Pos() Pos { return n.PosVal }
+
+func (n *Index) Accept(v Visitor) Node {
+	n.Receiver = n.Receiver.Accept(v)
+	n.Key = n.Key.Accept(v)
+	return v.Visit(n)
+}
+
+// JSONArray is a JSON array literal, such as [1, 2, a].
+type JSONArray struct {
+	PosVal   Pos
+	Elements []Node
+}
+
+func (n *JSONArray) Pos() Pos { return n.PosVal }
+
+func (n *JSONArray) Accept(v Visitor) Node {
+	for i, e := range n.Elements {
+		n.Elements[i] = e.Accept(v)
+	}
+	return v.Visit(n)
+}
+
+// JSONObjectEntry is a single "key": value pair of a JSONObject, kept in source order
+// since, unlike a Go map, a parsed object literal has a stable field order.
+type JSONObjectEntry struct {
+	Key   string
+	Value Node
+}
+
+// JSONObject is a JSON object literal, such as {"a": 1, "b": a}.
+type JSONObject struct {
+	PosVal  Pos
+	Entries []JSONObjectEntry
+}
+
+func (n *JSONObject) Pos() Pos { return n.PosVal }
+
+func (n *JSONObject) Accept(v Visitor) Node {
+	for i, e := range n.Entries {
+		n.Entries[i].Value = e.Value.Accept(v)
+	}
+	return v.Visit(n)
+}
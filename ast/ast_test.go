@@ -0,0 +1,79 @@
+package ast
+
+import "testing"
+
+// orderVisitor records the name of every Ident it visits, in visit order, so tests can
+// check that Accept visits children before the node itself.
+type orderVisitor struct {
+	order []string
+}
+
+func (v *orderVisitor) Visit(n Node) Node {
+	if id, ok := n.(*Ident); ok {
+		v.order = append(v.order, id.Name)
+	}
+	return n
+}
+
+func TestConditionalAcceptVisitsChildrenBeforeSelf(t *testing.T) {
+	n := &Conditional{
+		CondExpr:  &Ident{Name: "cond"},
+		TrueExpr:  &Ident{Name: "t"},
+		FalseExpr: &Ident{Name: "f"},
+	}
+	v := &orderVisitor{}
+	n.Accept(v)
+	want := []string{"cond", "t", "f"}
+	if len(v.order) != len(want) {
+		t.Fatalf("visit order = %v, want %v", v.order, want)
+	}
+	for i := range want {
+		if v.order[i] != want[i] {
+			t.Fatalf("visit order = %v, want %v", v.order, want)
+		}
+	}
+}
+
+// rewriteVisitor replaces every Ident named "old" with a Literal, to check that Accept
+// propagates a rewritten child back into its parent.
+type rewriteVisitor struct{}
+
+func (rewriteVisitor) Visit(n Node) Node {
+	if id, ok := n.(*Ident); ok && id.Name == "old" {
+		return &Literal{Value: "new"}
+	}
+	return n
+}
+
+func TestBinaryOpAcceptRewritesChildren(t *testing.T) {
+	n := &BinaryOp{Op: "+", Left: &Ident{Name: "old"}, Right: &Literal{Value: 1.0}}
+	got := n.Accept(rewriteVisitor{})
+	bin, ok := got.(*BinaryOp)
+	if !ok {
+		t.Fatalf("Accept returned %T, want *BinaryOp", got)
+	}
+	lit, ok := bin.Left.(*Literal)
+	if !ok || lit.Value != "new" {
+		t.Fatalf("Left = %#v, want a rewritten Literal", bin.Left)
+	}
+}
+
+func TestCallAcceptVisitsEachArg(t *testing.T) {
+	n := &Call{Name: "f", Args: []Node{&Ident{Name: "a"}, &Ident{Name: "b"}}}
+	v := &orderVisitor{}
+	n.Accept(v)
+	if len(v.order) != 2 || v.order[0] != "a" || v.order[1] != "b" {
+		t.Fatalf("visit order = %v, want [a b]", v.order)
+	}
+}
+
+func TestJSONObjectAcceptPreservesKeyOrder(t *testing.T) {
+	n := &JSONObject{Entries: []JSONObjectEntry{
+		{Key: "b", Value: &Ident{Name: "b"}},
+		{Key: "a", Value: &Ident{Name: "a"}},
+	}}
+	n.Accept(rewriteVisitor{})
+	if n.Entries[0].Key != "b" || n.Entries[1].Key != "a" {
+		t.Fatalf("entries reordered: %+v", n.Entries)
+	}
+}
@@ -0,0 +1,777 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/scanner"
+	"time"
+
+	"github.com/Gigaclank/gval/ast"
+)
+
+// Parse parses expression into a public ast.Node using its own hand-written lexer and
+// parser, mirroring the grammar of Full() as closely as this package can without access to
+// Full()'s own parsing internals: numbers, strings, idents (including dotted selectors
+// like a.b), unary - ! ~, the usual infix operators at their usual precedence, a ? b : c,
+// a[b] indexing only after an ident, call or another index, function calls and JSON
+// array/object literals. The result can be walked with an ast.Visitor to inspect or
+// rewrite the expression - for example a security allow-list that rejects any ast.Ident
+// not on its list - before handing it to EvaluateAST.
+//
+// Parse is a Language method so that EvaluateAST can evaluate a Call node against l
+// instead of only the handful of operators and functions built into this file, but l's
+// own operators and precedence are not consulted while parsing: an expression using a
+// custom InfixOperator or PrefixExtension that Full() does not already have fails to
+// parse with a syntax error, rather than silently parsing wrong. Because this grammar is
+// maintained separately from Full()'s, it is not guaranteed to accept and reject exactly
+// the same expressions Full() does; Parse(expression) is shorthand for
+// Full().Parse(expression).
+func (l Language) Parse(expression string) (ast.Node, error) {
+	p := &astParser{lx: newASTLexer(expression)}
+	n, err := p.parseExpr(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.lx.cur.kind != astTokEOF {
+		return nil, fmt.Errorf("unexpected token %q at %d", p.lx.cur.text, p.lx.cur.pos)
+	}
+	return n, nil
+}
+
+// Parse is shorthand for Full().Parse(expression).
+func Parse(expression string) (ast.Node, error) {
+	return full.Parse(expression)
+}
+
+// EvaluateAST evaluates node, as produced by Parse, against parameter using its own
+// tree-walking evaluator, which reimplements the operators of Full() plus any Function l
+// itself registers: a Call whose name isn't one of EvaluateAST's own builtins (currently
+// just date()) is evaluated by rendering its already-evaluated arguments back into gval
+// literals and running name(args...) through l.Evaluate, so a function registered on a
+// composed Language is still reachable from a Node. For expressions Parse accepts,
+// EvaluateAST(ctx, Parse(expr), parameter) is intended to agree with l.Evaluate(expr,
+// parameter), but since both the parser and evaluator here are maintained separately from
+// Full()'s, that agreement isn't guaranteed for every expr - only tested for the cases
+// Full()'s own grammar and this one both accept.
+func (l Language) EvaluateAST(ctx context.Context, node ast.Node, parameter interface{}) (interface{}, error) {
+	return evalASTNode(ctx, l, node, parameter)
+}
+
+// EvaluateAST is shorthand for Full().EvaluateAST(ctx, node, parameter).
+func EvaluateAST(ctx context.Context, node ast.Node, parameter interface{}) (interface{}, error) {
+	return full.EvaluateAST(ctx, node, parameter)
+}
+
+var astPrecedence = map[string]int{
+	"??": 0,
+	"||": 20, "&&": 21,
+	"==": 40, "!=": 40, ">": 40, ">=": 40, "<": 40, "<=": 40, "=~": 40, "!~": 40, "in": 40,
+	"^": 60, "&": 60, "|": 60,
+	"<<": 90, ">>": 90,
+	"+": 120, "-": 120,
+	"*": 150, "/": 150, "%": 150,
+	"**": 200,
+}
+
+type astTokenKind int
+
+const (
+	astTokEOF astTokenKind = iota
+	astTokNumber
+	astTokString
+	astTokIdent
+	astTokOp
+)
+
+type astToken struct {
+	kind astTokenKind
+	text string
+	pos  ast.Pos
+}
+
+type astLexer struct {
+	s   scanner.Scanner
+	cur astToken
+}
+
+var astTwoCharOps = map[string]bool{
+	"==": true, "!=": true, "<=": true, ">=": true, "&&": true, "||": true,
+	"**": true, "<<": true, ">>": true, "=~": true, "!~": true, "??": true,
+}
+
+func newASTLexer(expression string) *astLexer {
+	l := &astLexer{}
+	l.s.Init(strings.NewReader(expression))
+	l.s.Mode = scanner.ScanIdents | scanner.ScanInts | scanner.ScanFloats |
+		scanner.ScanStrings | scanner.ScanChars | scanner.ScanRawStrings
+	l.s.Error = func(*scanner.Scanner, string) {}
+	l.advance()
+	return l
+}
+
+func (l *astLexer) advance() {
+	r := l.s.Scan()
+	pos := ast.Pos(l.s.Position.Offset)
+	switch r {
+	case scanner.EOF:
+		l.cur = astToken{kind: astTokEOF, pos: pos}
+	case scanner.Int, scanner.Float:
+		l.cur = astToken{kind: astTokNumber, text: l.s.TokenText(), pos: pos}
+	case scanner.String, scanner.RawString, scanner.Char:
+		l.cur = astToken{kind: astTokString, text: l.s.TokenText(), pos: pos}
+	case scanner.Ident:
+		l.cur = astToken{kind: astTokIdent, text: l.s.TokenText(), pos: pos}
+	default:
+		text := string(r)
+		if next := l.s.Peek(); astTwoCharOps[text+string(next)] {
+			l.s.Next()
+			text += string(next)
+		}
+		l.cur = astToken{kind: astTokOp, text: text, pos: pos}
+	}
+}
+
+type astParser struct {
+	lx *astLexer
+}
+
+func (p *astParser) is(text string) bool {
+	return p.lx.cur.kind == astTokOp && p.lx.cur.text == text
+}
+
+func (p *astParser) expect(text string) error {
+	if !p.is(text) {
+		return fmt.Errorf("expected %q but got %q at %d", text, p.lx.cur.text, p.lx.cur.pos)
+	}
+	p.lx.advance()
+	return nil
+}
+
+func (p *astParser) parseExpr(minPrec int) (ast.Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	// in is scanned as an identifier rather than an operator token
+	for p.lx.cur.kind == astTokOp || (p.lx.cur.kind == astTokIdent && p.lx.cur.text == "in") {
+		prec, ok := astPrecedence[p.lx.cur.text]
+		if !ok || prec < minPrec {
+			break
+		}
+		op := p.lx.cur.text
+		pos := left.Pos()
+		p.lx.advance()
+		right, err := p.parseExpr(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &ast.BinaryOp{PosVal: pos, Op: op, Left: left, Right: right}
+	}
+	if minPrec == 0 && p.is("?") {
+		pos := left.Pos()
+		p.lx.advance()
+		trueExpr, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		falseExpr, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		left = &ast.Conditional{PosVal: pos, CondExpr: left, TrueExpr: trueExpr, FalseExpr: falseExpr}
+	}
+	return left, nil
+}
+
+func (p *astParser) parseUnary() (ast.Node, error) {
+	tok := p.lx.cur
+	if tok.kind == astTokOp && (tok.text == "-" || tok.text == "!" || tok.text == "~") {
+		p.lx.advance()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.PrefixOp{PosVal: tok.pos, Op: tok.text, Operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+// parsePostfix applies [b] indexing chains, but only after an Ident, Call or another
+// Index - the same receivers Full()'s real parser allows - not after a literal, JSON
+// array/object, or parenthesized expression, so Parse doesn't accept a strictly larger
+// grammar than Evaluate does.
+func (p *astParser) parsePostfix() (ast.Node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	switch n.(type) {
+	case *ast.Ident, *ast.Call, *ast.Index:
+	default:
+		return n, nil
+	}
+	for p.is("[") {
+		pos := p.lx.cur.pos
+		p.lx.advance()
+		key, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		n = &ast.Index{PosVal: pos, Receiver: n, Key: key}
+	}
+	return n, nil
+}
+
+func (p *astParser) parsePrimary() (ast.Node, error) {
+	tok := p.lx.cur
+	switch {
+	case tok.kind == astTokNumber:
+		p.lx.advance()
+		val, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s as number", tok.text)
+		}
+		return &ast.Literal{PosVal: tok.pos, Value: val}, nil
+
+	case tok.kind == astTokString:
+		p.lx.advance()
+		s, err := unquoteASTString(tok.text)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.Literal{PosVal: tok.pos, Value: s}, nil
+
+	case tok.kind == astTokIdent:
+		p.lx.advance()
+		name := tok.text
+		for p.is(".") {
+			p.lx.advance()
+			if p.lx.cur.kind != astTokIdent {
+				return nil, fmt.Errorf("expected identifier after . at %d", p.lx.cur.pos)
+			}
+			name += "." + p.lx.cur.text
+			p.lx.advance()
+		}
+		switch name {
+		case "true":
+			return &ast.Literal{PosVal: tok.pos, Value: true}, nil
+		case "false":
+			return &ast.Literal{PosVal: tok.pos, Value: false}, nil
+		}
+		if p.is("(") {
+			p.lx.advance()
+			var args []ast.Node
+			for !p.is(")") {
+				arg, err := p.parseExpr(0)
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if p.is(",") {
+					p.lx.advance()
+					continue
+				}
+				break
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return &ast.Call{PosVal: tok.pos, Name: name, Args: args}, nil
+		}
+		return &ast.Ident{PosVal: tok.pos, Name: name}, nil
+
+	case p.is("("):
+		p.lx.advance()
+		n, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return n, nil
+
+	case p.is("["):
+		return p.parseJSONArray()
+
+	case p.is("{"):
+		return p.parseJSONObject()
+	}
+	return nil, fmt.Errorf("unexpected token %q at %d", tok.text, tok.pos)
+}
+
+func (p *astParser) parseJSONArray() (ast.Node, error) {
+	pos := p.lx.cur.pos
+	p.lx.advance()
+	var elements []ast.Node
+	for !p.is("]") {
+		e, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, e)
+		if p.is(",") {
+			p.lx.advance()
+			continue
+		}
+		break
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return &ast.JSONArray{PosVal: pos, Elements: elements}, nil
+}
+
+func (p *astParser) parseJSONObject() (ast.Node, error) {
+	pos := p.lx.cur.pos
+	p.lx.advance()
+	var entries []ast.JSONObjectEntry
+	for !p.is("}") {
+		keyTok := p.lx.cur
+		if keyTok.kind != astTokString && keyTok.kind != astTokIdent {
+			return nil, fmt.Errorf("expected object key at %d", keyTok.pos)
+		}
+		p.lx.advance()
+		key := keyTok.text
+		if keyTok.kind == astTokString {
+			var err error
+			key, err = unquoteASTString(keyTok.text)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		val, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, ast.JSONObjectEntry{Key: key, Value: val})
+		if p.is(",") {
+			p.lx.advance()
+			continue
+		}
+		break
+	}
+	if err := p.expect("}"); err != nil {
+		return nil, err
+	}
+	return &ast.JSONObject{PosVal: pos, Entries: entries}, nil
+}
+
+func unquoteASTString(text string) (string, error) {
+	if strings.HasPrefix(text, "`") {
+		return strings.Trim(text, "`"), nil
+	}
+	return strconv.Unquote(text)
+}
+
+func evalASTNode(ctx context.Context, l Language, node ast.Node, parameter interface{}) (interface{}, error) {
+	switch n := node.(type) {
+	case *ast.Literal:
+		return n.Value, nil
+
+	case *ast.Ident:
+		return astLookup(parameter, n.Name)
+
+	case *ast.PrefixOp:
+		v, err := evalASTNode(ctx, l, n.Operand, parameter)
+		if err != nil {
+			return nil, err
+		}
+		switch n.Op {
+		case "-":
+			f, ok := convertToFloat(v)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %v(%T) expected number", v, v)
+			}
+			return -f, nil
+		case "!":
+			b, ok := convertToBool(v)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %T expected bool", v)
+			}
+			return !b, nil
+		case "~":
+			f, ok := convertToFloat(v)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %T expected number", v)
+			}
+			return float64(^int64(f)), nil
+		}
+		return nil, fmt.Errorf("unknown prefix operator %s", n.Op)
+
+	case *ast.BinaryOp:
+		return evalASTBinary(ctx, l, n, parameter)
+
+	case *ast.Conditional:
+		c, err := evalASTNode(ctx, l, n.CondExpr, parameter)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := convertToBool(c)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %T expected bool", c)
+		}
+		if b {
+			return evalASTNode(ctx, l, n.TrueExpr, parameter)
+		}
+		return evalASTNode(ctx, l, n.FalseExpr, parameter)
+
+	case *ast.Call:
+		args := make([]interface{}, len(n.Args))
+		for i, a := range n.Args {
+			v, err := evalASTNode(ctx, l, a, parameter)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = v
+		}
+		if n.Name != "date" {
+			return astCallFallback(l, n.Name, args)
+		}
+		if len(args) != 1 {
+			return nil, fmt.Errorf("date() expects exactly one string argument")
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("date() expects exactly one string argument")
+		}
+		return astParseDate(s)
+
+	case *ast.Index:
+		recv, err := evalASTNode(ctx, l, n.Receiver, parameter)
+		if err != nil {
+			return nil, err
+		}
+		key, err := evalASTNode(ctx, l, n.Key, parameter)
+		if err != nil {
+			return nil, err
+		}
+		return astIndex(recv, key)
+
+	case *ast.JSONArray:
+		arr := make([]interface{}, len(n.Elements))
+		for i, e := range n.Elements {
+			v, err := evalASTNode(ctx, l, e, parameter)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = v
+		}
+		return arr, nil
+
+	case *ast.JSONObject:
+		obj := make(map[string]interface{}, len(n.Entries))
+		for _, e := range n.Entries {
+			v, err := evalASTNode(ctx, l, e.Value, parameter)
+			if err != nil {
+				return nil, err
+			}
+			obj[e.Key] = v
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("unsupported node %T", node)
+}
+
+// astCallFallback evaluates a Call whose name is not one of EvaluateAST's own builtins by
+// rendering its already-evaluated args back into gval literal syntax and running
+// name(args...) through l.Evaluate, so a Function registered on a composed Language is
+// still usable from EvaluateAST instead of always failing with "unknown function".
+func astCallFallback(l Language, name string, args []interface{}) (interface{}, error) {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		lit, err := astValueLiteral(a)
+		if err != nil {
+			return nil, fmt.Errorf("calling %s: %s", name, err)
+		}
+		parts[i] = lit
+	}
+	return l.Evaluate(name+"("+strings.Join(parts, ", ")+")", nil)
+}
+
+// astValueLiteral renders v, one of the concrete types EvaluateAST produces, as gval
+// source so astCallFallback can splice it into a call expression.
+func astValueLiteral(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return strconv.Quote(x), nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64), nil
+	default:
+		return "", fmt.Errorf("cannot pass %v (%T) to a function not built into EvaluateAST", v, v)
+	}
+}
+
+func evalASTBinary(ctx context.Context, l Language, n *ast.BinaryOp, parameter interface{}) (interface{}, error) {
+	a, err := evalASTNode(ctx, l, n.Left, parameter)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Op == "&&" {
+		if b, ok := convertToBool(a); ok && !b {
+			return false, nil
+		}
+	}
+	if n.Op == "||" {
+		if b, ok := convertToBool(a); ok && b {
+			return true, nil
+		}
+	}
+	if n.Op == "??" {
+		if a != false && a != nil {
+			return a, nil
+		}
+	}
+
+	b, err := evalASTNode(ctx, l, n.Right, parameter)
+	if err != nil {
+		return nil, err
+	}
+	return astApplyBinary(n.Op, a, b)
+}
+
+// astApplyBinary computes op(a, b) once both operands are already evaluated. It is shared
+// by evalASTBinary, which evaluates the operands out of an ast.Node tree, and the VM's
+// binary opcodes, which pop them off the operand stack instead.
+func astApplyBinary(op string, a, b interface{}) (interface{}, error) {
+	switch op {
+	case "==":
+		aa, ok := a.([]interface{})
+		if !ok {
+			return reflect.DeepEqual(a, b), nil
+		}
+		for _, x := range aa {
+			if reflect.DeepEqual(x, b) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "!=":
+		aa, ok := a.([]interface{})
+		if !ok {
+			return !reflect.DeepEqual(a, b), nil
+		}
+		for _, x := range aa {
+			if !reflect.DeepEqual(x, b) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "??":
+		return b, nil
+	case "in":
+		return inMembership(a, b)
+	case "=~":
+		return astRegexMatch(a, b)
+	case "!~":
+		match, err := astRegexMatch(a, b)
+		if err != nil {
+			return nil, err
+		}
+		return !match.(bool), nil
+	}
+
+	if op == "+" {
+		if as, ok := a.(string); ok {
+			if bs, ok := b.(string); ok {
+				return as + bs, nil
+			}
+		}
+	}
+
+	switch op {
+	case "<", "<=", ">", ">=":
+		if as, ok := a.(string); ok {
+			if bs, ok := b.(string); ok {
+				switch op {
+				case "<":
+					return as < bs, nil
+				case "<=":
+					return as <= bs, nil
+				case ">":
+					return as > bs, nil
+				case ">=":
+					return as >= bs, nil
+				}
+			}
+		}
+	}
+
+	if op == "&&" || op == "||" {
+		ab, ok := convertToBool(a)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %T expected bool", a)
+		}
+		bb, ok := convertToBool(b)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %T expected bool", b)
+		}
+		if op == "&&" {
+			return ab && bb, nil
+		}
+		return ab || bb, nil
+	}
+
+	af, aok := convertToFloat(a)
+	bf, bok := convertToFloat(b)
+	if !aok {
+		return nil, fmt.Errorf("unexpected %v(%T) expected number", a, a)
+	}
+	if !bok {
+		return nil, fmt.Errorf("unexpected %v(%T) expected number", b, b)
+	}
+	switch op {
+	case "+":
+		return af + bf, nil
+	case "-":
+		return af - bf, nil
+	case "*":
+		return af * bf, nil
+	case "/":
+		return af / bf, nil
+	case "%":
+		return math.Mod(af, bf), nil
+	case "**":
+		return math.Pow(af, bf), nil
+	case "<":
+		return af < bf, nil
+	case "<=":
+		return af <= bf, nil
+	case ">":
+		return af > bf, nil
+	case ">=":
+		return af >= bf, nil
+	case "^":
+		return float64(int64(af) ^ int64(bf)), nil
+	case "&":
+		return float64(int64(af) & int64(bf)), nil
+	case "|":
+		return float64(int64(af) | int64(bf)), nil
+	case "<<":
+		return float64(int64(af) << uint64(bf)), nil
+	case ">>":
+		return float64(int64(af) >> uint64(bf)), nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", op)
+}
+
+// astRegexMatch implements =~ directly on a and b once both are already evaluated.
+// gval.go's "=~"/"!~" (InfixEvalOperator("=~", regEx) / ("!~", notRegEx)) take unevaluated
+// Evaluable thunks instead, so the regexp is only compiled if the match is reached; that
+// signature doesn't fit astApplyBinary, which always receives already-evaluated operands.
+func astRegexMatch(a, b interface{}) (interface{}, error) {
+	s, ok := a.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %T, expected string", a)
+	}
+	pattern, ok := b.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %T, expected string pattern", b)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString(s), nil
+}
+
+func astLookup(parameter interface{}, name string) (interface{}, error) {
+	cur := parameter
+	for _, part := range strings.Split(name, ".") {
+		switch m := cur.(type) {
+		case map[string]interface{}:
+			v, ok := m[part]
+			if !ok {
+				return nil, fmt.Errorf("unknown parameter %s", name)
+			}
+			cur = v
+			continue
+		}
+		rv := reflect.ValueOf(cur)
+		for rv.Kind() == reflect.Ptr {
+			rv = rv.Elem()
+		}
+		if rv.Kind() == reflect.Struct {
+			f := rv.FieldByName(part)
+			if f.IsValid() {
+				if !f.CanInterface() {
+					return nil, fmt.Errorf("field %s of parameter %s is unexported", part, name)
+				}
+				cur = f.Interface()
+				continue
+			}
+		}
+		return nil, fmt.Errorf("unknown parameter %s", name)
+	}
+	return cur, nil
+}
+
+func astIndex(receiver, key interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(receiver)
+	switch rv.Kind() {
+	case reflect.Map:
+		kv := reflect.ValueOf(key)
+		if !kv.IsValid() || !kv.Type().ConvertibleTo(rv.Type().Key()) {
+			return nil, fmt.Errorf("cannot index %T with %T", receiver, key)
+		}
+		v := rv.MapIndex(kv.Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return nil, nil
+		}
+		return v.Interface(), nil
+	case reflect.Slice, reflect.Array:
+		i, ok := convertToFloat(key)
+		if !ok {
+			return nil, fmt.Errorf("cannot index %T with %T", receiver, key)
+		}
+		idx := int(i)
+		if idx < 0 || idx >= rv.Len() {
+			return nil, fmt.Errorf("index %d out of range", idx)
+		}
+		return rv.Index(idx).Interface(), nil
+	}
+	return nil, fmt.Errorf("unexpected %T, expected map, slice or array", receiver)
+}
+
+var astDateFormats = [...]string{
+	time.ANSIC,
+	time.UnixDate,
+	time.RubyDate,
+	time.Kitchen,
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02T15Z0700",
+	"2006-01-02T15:04Z0700",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05.999999999Z0700",
+}
+
+func astParseDate(s string) (interface{}, error) {
+	for _, format := range astDateFormats {
+		if ret, err := time.ParseInLocation(format, s, time.Local); err == nil {
+			return ret, nil
+		}
+	}
+	return nil, fmt.Errorf("date() could not parse %s", s)
+}
@@ -0,0 +1,132 @@
+package gval
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Gigaclank/gval/ast"
+)
+
+func TestParseEvaluateASTMatchesEvaluate(t *testing.T) {
+	tests := []struct {
+		expr      string
+		parameter interface{}
+	}{
+		{"1 + 2 * 3", nil},
+		{`"a" + "b"`, nil},
+		{"a.b", map[string]interface{}{"a": map[string]interface{}{"b": 42.0}}},
+		{"a > 1 && b < 10", map[string]interface{}{"a": 5.0, "b": 3.0}},
+		{"a ?? b", map[string]interface{}{"a": nil, "b": 7.0}},
+		{"[1,2,3] == 2", nil},
+		{"[1,2,3] != 2", nil},
+		{`{"a": 1, "b": 2}`, nil},
+		{"a ? 1 : 2", map[string]interface{}{"a": false}},
+	}
+	for _, tt := range tests {
+		node, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %s", tt.expr, err)
+		}
+		got, err := EvaluateAST(context.Background(), node, tt.parameter)
+		if err != nil {
+			t.Fatalf("EvaluateAST(%q): %s", tt.expr, err)
+		}
+		want, err := Evaluate(tt.expr, tt.parameter)
+		if err != nil {
+			t.Fatalf("Evaluate(%q): %s", tt.expr, err)
+		}
+		gotArr, gotIsArr := got.([]interface{})
+		wantArr, wantIsArr := want.([]interface{})
+		if gotIsArr && wantIsArr {
+			if len(gotArr) != len(wantArr) {
+				t.Errorf("EvaluateAST(Parse(%q)) = %v, Evaluate(%q) = %v", tt.expr, got, tt.expr, want)
+			}
+			continue
+		}
+		if gotMap, ok := got.(map[string]interface{}); ok {
+			if !reflect.DeepEqual(gotMap, want) {
+				t.Errorf("EvaluateAST(Parse(%q)) = %v, want Evaluate(%q) = %v", tt.expr, got, tt.expr, want)
+			}
+			continue
+		}
+		if got != want {
+			t.Errorf("EvaluateAST(Parse(%q)) = %v, want Evaluate(%q) = %v", tt.expr, got, tt.expr, want)
+		}
+	}
+}
+
+func TestASTLookupUnexportedFieldErrors(t *testing.T) {
+	type withUnexported struct {
+		unexported int
+	}
+	node, err := Parse("a.unexported")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = EvaluateAST(context.Background(), node, map[string]interface{}{"a": withUnexported{unexported: 1}})
+	if err == nil {
+		t.Fatal("expected an error for an unexported struct field, got nil")
+	}
+}
+
+func TestASTLookupExportedField(t *testing.T) {
+	type withExported struct {
+		Exported float64
+	}
+	node, err := Parse("a.Exported")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := EvaluateAST(context.Background(), node, map[string]interface{}{"a": withExported{Exported: 9}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 9.0 {
+		t.Errorf("a.Exported = %v, want 9", got)
+	}
+}
+
+func TestVisitorRewritesIdentBeforeEvaluateAST(t *testing.T) {
+	node, err := Parse("a + 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	node = node.Accept(renameVisitor{from: "a", to: "b"})
+	got, err := EvaluateAST(context.Background(), node, map[string]interface{}{"b": 4.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 5.0 {
+		t.Errorf("got %v, want 5", got)
+	}
+}
+
+func TestEvaluateASTCallsCustomFunction(t *testing.T) {
+	l := NewLanguage(
+		Function("double", func(arguments ...interface{}) (interface{}, error) {
+			return 2 * arguments[0].(float64), nil
+		}),
+		Full(),
+	)
+	node, err := l.Parse("double(21)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := l.EvaluateAST(context.Background(), node, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 42.0 {
+		t.Errorf("double(21) = %v, want 42", got)
+	}
+}
+
+type renameVisitor struct{ from, to string }
+
+func (r renameVisitor) Visit(n ast.Node) ast.Node {
+	if id, ok := n.(*ast.Ident); ok && id.Name == r.from {
+		id.Name = r.to
+	}
+	return n
+}
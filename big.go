@@ -0,0 +1,344 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"text/scanner"
+)
+
+// BigMode selects the representation BigNumber falls back to when an expression
+// does not already force one through division or a float literal.
+type BigMode uint
+
+const (
+	// BigModeInt keeps integer literals and integer results as *big.Int. This is the default.
+	BigModeInt BigMode = iota
+	// BigModeRat parses integer literals as *big.Rat, so every intermediate result is an exact fraction.
+	BigModeRat
+	// BigModeFloat parses integer literals as *big.Float, the same as float literals.
+	BigModeFloat
+)
+
+type bigOptions struct {
+	mode      BigMode
+	floatPrec uint
+}
+
+// BigOption configures a BigNumber Language.
+type BigOption func(*bigOptions)
+
+// WithBigFloatPrec sets the mantissa precision, in bits, used for *big.Float literals
+// and for any operation promoted to floating point. The default is 53, matching float64.
+func WithBigFloatPrec(prec uint) BigOption {
+	return func(o *bigOptions) { o.floatPrec = prec }
+}
+
+// WithBigMode sets the representation integer literals start out as. The default is
+// BigModeInt, so whole numbers stay exact until an operation (such as /) forces a promotion.
+func WithBigMode(mode BigMode) BigOption {
+	return func(o *bigOptions) { o.mode = mode }
+}
+
+// BigNumber contains base, plus(+), minus(-), divide(/), modulo(%), power(**) and
+// numerical order (==,!=,<=,<,>,>=), evaluated with math/big instead of float64.
+//
+// Integer literals are parsed as *big.Int, so expressions such as 2**100 > 1000000000000
+// do not lose precision the way float64 would. Float literals are parsed as *big.Float at
+// the precision set by WithBigFloatPrec (default 53). Mixed operations promote the same
+// way Go's untyped constants do: Int op Int stays Int, except / which always promotes its
+// result to Rat (or Float, see WithBigMode); a Rat operand promotes the whole operation to
+// Rat, and a Float operand always wins, promoting to Float.
+//
+// % accepts only integer operands and reports an error for a Rat or Float operand. **
+// stays exact only for an Int base and a non-negative Int exponent; any other ** is
+// computed with float64 math.Pow, so its result has only float64 precision and range.
+func BigNumber(opts ...BigOption) Language {
+	o := &bigOptions{mode: BigModeInt, floatPrec: 53}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return NewLanguage(
+		PrefixExtension(scanner.Int, parseBigNumber(o, false)),
+		PrefixExtension(scanner.Float, parseBigNumber(o, true)),
+
+		PrefixOperator("-", func(c context.Context, v interface{}) (interface{}, error) {
+			return bigNegate(v)
+		}),
+
+		InfixOperator("+", bigArith(o, (*big.Int).Add, (*big.Rat).Add, (*big.Float).Add)),
+		InfixOperator("-", bigArith(o, (*big.Int).Sub, (*big.Rat).Sub, (*big.Float).Sub)),
+		InfixOperator("*", bigArith(o, (*big.Int).Mul, (*big.Rat).Mul, (*big.Float).Mul)),
+		InfixOperator("/", bigDivide(o)),
+		InfixOperator("%", bigMod),
+		InfixOperator("**", bigPow(o)),
+
+		InfixOperator(">", bigCompare(o, func(c int) bool { return c > 0 })),
+		InfixOperator(">=", bigCompare(o, func(c int) bool { return c >= 0 })),
+		InfixOperator("<", bigCompare(o, func(c int) bool { return c < 0 })),
+		InfixOperator("<=", bigCompare(o, func(c int) bool { return c <= 0 })),
+		InfixOperator("==", bigCompare(o, func(c int) bool { return c == 0 })),
+		InfixOperator("!=", bigCompare(o, func(c int) bool { return c != 0 })),
+
+		base,
+	)
+}
+
+type bigKind int
+
+const (
+	bigKindInt bigKind = iota
+	bigKindRat
+	bigKindFloat
+)
+
+// parseBigNumber parses the current token's text as a number. isFloatLiteral reports
+// whether the scanner recognized the token as a float (a decimal point or exponent), as
+// opposed to an integer; only integer literals are eligible for the Int/Rat fast paths
+// below, so a float literal such as 1.5 always becomes a *big.Float, honoring
+// WithBigFloatPrec regardless of WithBigMode.
+func parseBigNumber(o *bigOptions, isFloatLiteral bool) func(c context.Context, p *Parser) (Evaluable, error) {
+	return func(c context.Context, p *Parser) (Evaluable, error) {
+		text := p.TokenText()
+
+		if !isFloatLiteral && o.mode == BigModeInt {
+			if i, ok := new(big.Int).SetString(text, 10); ok {
+				return p.Const(i), nil
+			}
+		}
+		if !isFloatLiteral && o.mode == BigModeRat {
+			if r, ok := new(big.Rat).SetString(text); ok {
+				return p.Const(r), nil
+			}
+		}
+		f, ok := new(big.Float).SetPrec(o.floatPrec).SetString(text)
+		if !ok {
+			return nil, fmt.Errorf("could not parse %s as number", text)
+		}
+		return p.Const(f), nil
+	}
+}
+
+// toBig normalizes v, which may already be a *big.Int/*big.Rat/*big.Float or any of gval's
+// usual numeric kinds, into one of the three big representations.
+func toBig(v interface{}) (val interface{}, kind bigKind, err error) {
+	switch n := v.(type) {
+	case *big.Int:
+		return n, bigKindInt, nil
+	case *big.Rat:
+		return n, bigKindRat, nil
+	case *big.Float:
+		return n, bigKindFloat, nil
+	case int:
+		return big.NewInt(int64(n)), bigKindInt, nil
+	case int64:
+		return big.NewInt(n), bigKindInt, nil
+	case float64:
+		return big.NewFloat(n), bigKindFloat, nil
+	case string:
+		if i, ok := new(big.Int).SetString(n, 10); ok {
+			return i, bigKindInt, nil
+		}
+		if f, ok := new(big.Float).SetString(n); ok {
+			return f, bigKindFloat, nil
+		}
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), bigKindInt, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), bigKindInt, nil
+	case reflect.Float32, reflect.Float64:
+		return big.NewFloat(rv.Float()), bigKindFloat, nil
+	}
+	return nil, 0, fmt.Errorf("unexpected %T, expected number", v)
+}
+
+func convertBig(v interface{}, from, to bigKind, prec uint) interface{} {
+	if from == to {
+		return v
+	}
+	switch to {
+	case bigKindRat:
+		if x, ok := v.(*big.Int); ok {
+			return new(big.Rat).SetInt(x)
+		}
+	case bigKindFloat:
+		f := new(big.Float).SetPrec(prec)
+		switch x := v.(type) {
+		case *big.Int:
+			return f.SetInt(x)
+		case *big.Rat:
+			return f.SetRat(x)
+		}
+	}
+	return v
+}
+
+// promoteBig converts a and b to a shared big representation, the least precise of the two
+// that can hold both values (Int < Rat < Float), and reports that representation.
+func promoteBig(a, b interface{}, prec uint) (av, bv interface{}, kind bigKind, err error) {
+	av, ak, err := toBig(a)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	bv, bk, err := toBig(b)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	kind = ak
+	if bk > kind {
+		kind = bk
+	}
+	return convertBig(av, ak, kind, prec), convertBig(bv, bk, kind, prec), kind, nil
+}
+
+func bigArith(
+	o *bigOptions,
+	intOp func(z, x, y *big.Int) *big.Int,
+	ratOp func(z, x, y *big.Rat) *big.Rat,
+	floatOp func(z, x, y *big.Float) *big.Float,
+) func(a, b interface{}) (interface{}, error) {
+	return func(a, b interface{}) (interface{}, error) {
+		av, bv, kind, err := promoteBig(a, b, o.floatPrec)
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case bigKindInt:
+			return intOp(new(big.Int), av.(*big.Int), bv.(*big.Int)), nil
+		case bigKindRat:
+			return ratOp(new(big.Rat), av.(*big.Rat), bv.(*big.Rat)), nil
+		default:
+			return floatOp(new(big.Float).SetPrec(o.floatPrec), av.(*big.Float), bv.(*big.Float)), nil
+		}
+	}
+}
+
+func bigDivide(o *bigOptions) func(a, b interface{}) (interface{}, error) {
+	return func(a, b interface{}) (interface{}, error) {
+		av, bv, kind, err := promoteBig(a, b, o.floatPrec)
+		if err != nil {
+			return nil, err
+		}
+		if kind == bigKindInt {
+			// division always leaves integers: Int / Int -> Rat (or Float in BigModeFloat)
+			kind = bigKindRat
+			if o.mode == BigModeFloat {
+				kind = bigKindFloat
+			}
+			av = convertBig(av, bigKindInt, kind, o.floatPrec)
+			bv = convertBig(bv, bigKindInt, kind, o.floatPrec)
+		}
+		switch kind {
+		case bigKindRat:
+			divisor := bv.(*big.Rat)
+			if divisor.Sign() == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return new(big.Rat).Quo(av.(*big.Rat), divisor), nil
+		default:
+			divisor := bv.(*big.Float)
+			if divisor.Sign() == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return new(big.Float).SetPrec(o.floatPrec).Quo(av.(*big.Float), divisor), nil
+		}
+	}
+}
+
+func bigMod(a, b interface{}) (interface{}, error) {
+	av, ak, err := toBig(a)
+	if err != nil {
+		return nil, err
+	}
+	bv, bk, err := toBig(b)
+	if err != nil {
+		return nil, err
+	}
+	if ak != bigKindInt || bk != bigKindInt {
+		return nil, fmt.Errorf("%% expects integer operands")
+	}
+	divisor := bv.(*big.Int)
+	if divisor.Sign() == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+	return new(big.Int).Mod(av.(*big.Int), divisor), nil
+}
+
+func bigPow(o *bigOptions) func(a, b interface{}) (interface{}, error) {
+	return func(a, b interface{}) (interface{}, error) {
+		av, ak, err := toBig(a)
+		if err != nil {
+			return nil, err
+		}
+		bv, bk, err := toBig(b)
+		if err != nil {
+			return nil, err
+		}
+		if ak == bigKindInt && bk == bigKindInt {
+			if exp := bv.(*big.Int); exp.Sign() >= 0 {
+				return new(big.Int).Exp(av.(*big.Int), exp, nil), nil
+			}
+		}
+		base := new(big.Float).SetPrec(o.floatPrec)
+		switch x := av.(type) {
+		case *big.Int:
+			base.SetInt(x)
+		case *big.Rat:
+			base.SetRat(x)
+		case *big.Float:
+			base.Set(x)
+		}
+		exp := new(big.Float).SetPrec(o.floatPrec)
+		switch x := bv.(type) {
+		case *big.Int:
+			exp.SetInt(x)
+		case *big.Rat:
+			exp.SetRat(x)
+		case *big.Float:
+			exp.Set(x)
+		}
+		baseF, _ := base.Float64()
+		expF, _ := exp.Float64()
+		return new(big.Float).SetPrec(o.floatPrec).SetFloat64(math.Pow(baseF, expF)), nil
+	}
+}
+
+func bigCompare(o *bigOptions, judge func(int) bool) func(a, b interface{}) (interface{}, error) {
+	return func(a, b interface{}) (interface{}, error) {
+		av, bv, kind, err := promoteBig(a, b, o.floatPrec)
+		if err != nil {
+			return nil, err
+		}
+		var cmp int
+		switch kind {
+		case bigKindInt:
+			cmp = av.(*big.Int).Cmp(bv.(*big.Int))
+		case bigKindRat:
+			cmp = av.(*big.Rat).Cmp(bv.(*big.Rat))
+		default:
+			cmp = av.(*big.Float).Cmp(bv.(*big.Float))
+		}
+		return judge(cmp), nil
+	}
+}
+
+func bigNegate(v interface{}) (interface{}, error) {
+	val, kind, err := toBig(v)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case bigKindInt:
+		return new(big.Int).Neg(val.(*big.Int)), nil
+	case bigKindRat:
+		return new(big.Rat).Neg(val.(*big.Rat)), nil
+	default:
+		return new(big.Float).Neg(val.(*big.Float)), nil
+	}
+}
@@ -0,0 +1,96 @@
+package gval
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBigNumberPrecision(t *testing.T) {
+	got, err := Evaluate("2**100 > 1000000000000", nil, BigNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != true {
+		t.Errorf("2**100 > 1000000000000 = %v, want true", got)
+	}
+}
+
+func TestBigNumberArithmetic(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"1 + 2", "3"},
+		{"7 % 2", "1"},
+		{"2 ** 10", "1024"},
+		{"-(5)", "-5"},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.expr, nil, BigNumber())
+		if err != nil {
+			t.Fatalf("Evaluate(%q): %s", tt.expr, err)
+		}
+		if fs := fmtBig(got); fs != tt.want {
+			t.Errorf("Evaluate(%q) = %s, want %s", tt.expr, fs, tt.want)
+		}
+	}
+}
+
+func TestBigNumberDivisionPromotesToRat(t *testing.T) {
+	got, err := Evaluate("1 / 3", nil, BigNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs := fmtBig(got); fs != "1/3" {
+		t.Errorf("1 / 3 = %s, want 1/3", fs)
+	}
+}
+
+func TestBigNumberFloatLiteralPromotes(t *testing.T) {
+	got, err := Evaluate("1 + 0.5", nil, BigNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs := fmtBig(got); fs != "1.5" {
+		t.Errorf("1 + 0.5 = %s, want 1.5", fs)
+	}
+}
+
+func TestBigNumberDivideByZero(t *testing.T) {
+	if _, err := Evaluate("1 / 0", nil, BigNumber()); err == nil {
+		t.Fatal("expected a division by zero error")
+	}
+}
+
+func TestBigNumberModeRat(t *testing.T) {
+	got, err := Evaluate("3", nil, BigNumber(WithBigMode(BigModeRat)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs := fmtBig(got); fs != "3" {
+		t.Errorf("3 under BigModeRat = %s, want 3", fs)
+	}
+}
+
+// TestBigNumberModeRatFloatLiteralStaysFloat guards against a float-syntax literal like
+// 1.5 being parsed as an exact *big.Rat under BigModeRat/BigModeInt, which would silently
+// ignore WithBigFloatPrec.
+func TestBigNumberModeRatFloatLiteralStaysFloat(t *testing.T) {
+	got, err := Evaluate("1.5", nil, BigNumber(WithBigMode(BigModeRat)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.(*big.Float); !ok {
+		t.Errorf("1.5 under BigModeRat = %T, want *big.Float", got)
+	}
+}
+
+// fmtBig stringifies the *big.Int/*big.Rat/*big.Float BigNumber returns so tests can
+// compare against a plain expected string regardless of which representation won.
+func fmtBig(v interface{}) string {
+	type stringer interface{ String() string }
+	if s, ok := v.(stringer); ok {
+		return s.String()
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package gval
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// InOperandError reports that a in b could not be evaluated: either b's kind does not
+// support membership testing, or a is not convertible to b's element or key type.
+type InOperandError struct {
+	Operator string
+	Value    interface{}
+	Kind     reflect.Kind
+}
+
+func (e *InOperandError) Error() string {
+	return fmt.Sprintf("%s: unsupported right-hand operand %v (%s)", e.Operator, e.Value, e.Kind)
+}
+
+// inMembership reports whether a is an element of b. Unlike a plain []interface{} membership
+// check, it uses reflect.Value on b so that k in m tests key membership when m is a map,
+// sub in s tests substring containment when s is a string, and element membership works
+// for a slice or array of any concrete element type - coercing a to that element or key
+// type when it is convertible, as Salix does with a.Convert(b.Type().Elem()). An
+// unsupported b kind, or an a that is not convertible, reports an *InOperandError instead
+// of silently returning false.
+func inMembership(a, b interface{}) (interface{}, error) {
+	if s, ok := b.(string); ok {
+		sub, ok := a.(string)
+		if !ok {
+			return nil, &InOperandError{Operator: "in", Value: b, Kind: reflect.String}
+		}
+		return strings.Contains(s, sub), nil
+	}
+
+	bv := reflect.ValueOf(b)
+	switch bv.Kind() {
+	case reflect.Map:
+		keyType := bv.Type().Key()
+		av := reflect.ValueOf(a)
+		if !av.IsValid() || !av.Type().ConvertibleTo(keyType) {
+			return nil, &InOperandError{Operator: "in", Value: b, Kind: bv.Kind()}
+		}
+		return bv.MapIndex(av.Convert(keyType)).IsValid(), nil
+
+	case reflect.Slice, reflect.Array:
+		elemType := bv.Type().Elem()
+		av := reflect.ValueOf(a)
+		if !av.IsValid() || !av.Type().ConvertibleTo(elemType) {
+			return nil, &InOperandError{Operator: "in", Value: b, Kind: bv.Kind()}
+		}
+		av = av.Convert(elemType)
+		for i := 0; i < bv.Len(); i++ {
+			if reflect.DeepEqual(av.Interface(), bv.Index(i).Interface()) {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	default:
+		return nil, &InOperandError{Operator: "in", Value: b, Kind: bv.Kind()}
+	}
+}
@@ -0,0 +1,63 @@
+package gval
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInArrayMembership(t *testing.T) {
+	tests := []struct {
+		a, b interface{}
+		want bool
+	}{
+		{2, []int{1, 2, 3}, true},
+		{4, []int{1, 2, 3}, false},
+		{int32(2), []int{1, 2, 3}, true}, // convertible numeric kind
+		{"b", map[string]int{"a": 1, "b": 2}, true},
+		{"c", map[string]int{"a": 1, "b": 2}, false},
+		{"ell", "hello", true},
+		{"xyz", "hello", false},
+	}
+	for _, tt := range tests {
+		got, err := inMembership(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("inMembership(%v, %v): %s", tt.a, tt.b, err)
+		}
+		if got != tt.want {
+			t.Errorf("inMembership(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestInArrayNonConvertibleReportsError(t *testing.T) {
+	_, err := inMembership("3", []int{1, 2, 3})
+	if err == nil {
+		t.Fatal("expected an error for a non-convertible left operand, got nil")
+	}
+	var inErr *InOperandError
+	if !errors.As(err, &inErr) {
+		t.Fatalf("expected an *InOperandError, got %T: %s", err, err)
+	}
+	if inErr.Kind != reflect.Slice {
+		t.Errorf("Kind = %v, want Slice", inErr.Kind)
+	}
+}
+
+func TestInArrayUnsupportedKindReportsError(t *testing.T) {
+	_, err := inMembership(1, 2)
+	if err == nil {
+		t.Fatal("expected an error for a non-membership-testable right operand, got nil")
+	}
+	var inErr *InOperandError
+	if !errors.As(err, &inErr) {
+		t.Fatalf("expected an *InOperandError, got %T: %s", err, err)
+	}
+}
+
+func TestInArrayMapKeyNonConvertibleReportsError(t *testing.T) {
+	_, err := inMembership([]int{1}, map[string]int{"a": 1})
+	if err == nil {
+		t.Fatal("expected an error for a non-convertible map key, got nil")
+	}
+}
@@ -0,0 +1,106 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// likePatternCache holds the compiled regexp for each LIKE/ILIKE pattern seen so far,
+// since the right-hand side is typically a constant literal evaluated on every call.
+var likePatternCache sync.Map // map[string]*regexp.Regexp
+
+// SQL contains base, plus the SQL-style like and ilike operators at the same precedence
+// tier as =~, and a ! prefix operator so a like b can be negated as !(a like b).
+//
+// a like b treats b as a pattern where % matches any run of characters and _ matches any
+// single character, with \ as the escape character; ilike is the case-insensitive variant.
+// The pattern is compiled into an anchored regexp once per distinct pattern string and
+// cached, rather than recompiled on every evaluation.
+//
+// SQL has no "not like"/"not ilike" operator: a lexer that scans "not" and "like" as two
+// separate identifier tokens, the same way in and like themselves are scanned, has no way
+// to match an operator name containing a space, so registering one under that name would
+// just be dead code. Negation composes instead, the same way ! already negates any other
+// boolean-returning operator in this package.
+func SQL() Language {
+	return NewLanguage(
+		InfixOperator("like", likeOperator(false)),
+		InfixOperator("ilike", likeOperator(true)),
+
+		Precedence("like", 40),
+		Precedence("ilike", 40),
+
+		PrefixOperator("!", func(c context.Context, v interface{}) (interface{}, error) {
+			b, ok := convertToBool(v)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %T expected bool", v)
+			}
+			return !b, nil
+		}),
+
+		base,
+	)
+}
+
+func likeOperator(caseInsensitive bool) func(a, b interface{}) (interface{}, error) {
+	return func(a, b interface{}) (interface{}, error) {
+		s, ok := a.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %T, expected string", a)
+		}
+		pattern, ok := b.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %T, expected string pattern", b)
+		}
+		re, err := likeRegexp(pattern, caseInsensitive)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(s), nil
+	}
+}
+
+// likeRegexp compiles pattern into an anchored regexp the first time it is seen for a
+// given case-sensitivity, and reuses it afterwards.
+func likeRegexp(pattern string, caseInsensitive bool) (*regexp.Regexp, error) {
+	key := pattern
+	if caseInsensitive {
+		key = "i:" + pattern
+	}
+	if cached, ok := likePatternCache.Load(key); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if caseInsensitive {
+		b.WriteString("(?i)")
+	}
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			b.WriteString(".*")
+		case r == '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid like pattern %q: %s", pattern, err)
+	}
+	actual, _ := likePatternCache.LoadOrStore(key, re)
+	return actual.(*regexp.Regexp), nil
+}
@@ -0,0 +1,64 @@
+package gval
+
+import "testing"
+
+func TestSQLLike(t *testing.T) {
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{`"hello" like "h%"`, true},
+		{`"hello" like "H%"`, false},
+		{`"hello" ilike "H%"`, true},
+		{`"hello" like "h_llo"`, true},
+		{`"hello" like "world"`, false},
+		{`!("hello" like "world")`, true},
+		{`!("hello" like "h%")`, false},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.expr, nil, SQL())
+		if err != nil {
+			t.Fatalf("Evaluate(%q): %s", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Errorf("Evaluate(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestSQLLikeTypeErrors(t *testing.T) {
+	if _, err := Evaluate(`1 like "a"`, nil, SQL()); err == nil {
+		t.Fatal("expected an error for a non-string left operand")
+	}
+}
+
+func TestLikeRegexpCache(t *testing.T) {
+	re1, err := likeRegexp("a%b", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	re2, err := likeRegexp("a%b", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if re1 != re2 {
+		t.Error("expected likeRegexp to reuse the cached *regexp.Regexp for the same pattern")
+	}
+	re3, err := likeRegexp("a%b", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if re3 == re1 {
+		t.Error("expected likeRegexp to cache case-sensitive and case-insensitive patterns separately")
+	}
+}
+
+func TestLikeOperatorDirect(t *testing.T) {
+	op := likeOperator(false)
+	if _, err := op(42, "a%"); err == nil {
+		t.Fatal("expected an error for a non-string left operand")
+	}
+	if _, err := op("a", 42); err == nil {
+		t.Fatal("expected an error for a non-string pattern")
+	}
+}
@@ -0,0 +1,262 @@
+package gval
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// divisionMode controls what / does for two integer operands in TypedArithmetic.
+type divisionMode uint
+
+const (
+	integerDivision divisionMode = iota
+	realDivision
+)
+
+type typedArithmeticOptions struct {
+	division divisionMode
+}
+
+// TypedArithmeticOption configures a TypedArithmetic Language.
+type TypedArithmeticOption func(*typedArithmeticOptions)
+
+// WithRealDivision makes / always return a float64, even when both operands are integers,
+// instead of the default Go-style truncating integer division.
+func WithRealDivision() TypedArithmeticOption {
+	return func(o *typedArithmeticOptions) { o.division = realDivision }
+}
+
+// TypedArithmetic contains base, plus(+), minus(-), times(*), divide(/), modulo(%) and
+// numerical order (==,!=,<=,<,>,>=), like Arithmetic, but without coercing every operand
+// through float64 first.
+//
+// Operands keep their int64/uint64/float64 identity for as long as possible: int op int
+// stays int64, uint op uint stays uint64, and a float operand on either side promotes both
+// to float64. Mixing a signed and an unsigned integer promotes to int64 if the signed
+// operand is negative, otherwise to uint64 - the same rule Hugo's template arithmetic
+// uses. / keeps its result an integer when both operands are integers, unless
+// WithRealDivision is set.
+func TypedArithmetic(opts ...TypedArithmeticOption) Language {
+	o := &typedArithmeticOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return NewLanguage(
+		InfixOperator("+", typedArith(
+			func(a, b int64) (interface{}, error) { return a + b, nil },
+			func(a, b uint64) (interface{}, error) { return a + b, nil },
+			func(a, b float64) (interface{}, error) { return a + b, nil },
+		)),
+		InfixOperator("-", typedArith(
+			func(a, b int64) (interface{}, error) { return a - b, nil },
+			func(a, b uint64) (interface{}, error) { return a - b, nil },
+			func(a, b float64) (interface{}, error) { return a - b, nil },
+		)),
+		InfixOperator("*", typedArith(
+			func(a, b int64) (interface{}, error) { return a * b, nil },
+			func(a, b uint64) (interface{}, error) { return a * b, nil },
+			func(a, b float64) (interface{}, error) { return a * b, nil },
+		)),
+		InfixOperator("%", typedArith(
+			func(a, b int64) (interface{}, error) {
+				if b == 0 {
+					return nil, fmt.Errorf("division by zero")
+				}
+				return a % b, nil
+			},
+			func(a, b uint64) (interface{}, error) {
+				if b == 0 {
+					return nil, fmt.Errorf("division by zero")
+				}
+				return a % b, nil
+			},
+			func(a, b float64) (interface{}, error) { return math.Mod(a, b), nil },
+		)),
+		InfixOperator("/", typedDivide(o)),
+
+		InfixOperator(">", typedCompare(func(c int) bool { return c > 0 })),
+		InfixOperator(">=", typedCompare(func(c int) bool { return c >= 0 })),
+		InfixOperator("<", typedCompare(func(c int) bool { return c < 0 })),
+		InfixOperator("<=", typedCompare(func(c int) bool { return c <= 0 })),
+		InfixOperator("==", typedCompare(func(c int) bool { return c == 0 })),
+		InfixOperator("!=", typedCompare(func(c int) bool { return c != 0 })),
+
+		base,
+	)
+}
+
+// numericValue extracts v as an int64, uint64 or float64, reporting which of the three
+// it picked as a reflect.Kind of Int64, Uint64 or Float64.
+func numericValue(v interface{}) (interface{}, reflect.Kind, error) {
+	switch n := v.(type) {
+	case string:
+		if i, err := strconv.ParseInt(n, 10, 64); err == nil {
+			return i, reflect.Int64, nil
+		}
+		if f, err := strconv.ParseFloat(n, 64); err == nil {
+			return f, reflect.Float64, nil
+		}
+		return nil, 0, fmt.Errorf("unexpected string %q, expected number", n)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), reflect.Int64, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint(), reflect.Uint64, nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), reflect.Float64, nil
+	default:
+		return nil, 0, fmt.Errorf("unexpected %T, expected number", v)
+	}
+}
+
+// promoteNumeric promotes a and b to a shared int64, uint64 or float64 representation,
+// following the same promotion rules as Hugo's doArithmetic.
+func promoteNumeric(a, b interface{}) (av, bv interface{}, kind reflect.Kind, err error) {
+	an, ak, err := numericValue(a)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	bn, bk, err := numericValue(b)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if ak == reflect.Float64 || bk == reflect.Float64 {
+		return toFloat64Kind(an, ak), toFloat64Kind(bn, bk), reflect.Float64, nil
+	}
+	if ak == bk {
+		return an, bn, ak, nil
+	}
+
+	// one side is int64, the other uint64: a negative operand forces int64, otherwise uint64
+	ai, aIsInt := an.(int64)
+	bi, bIsInt := bn.(int64)
+	if aIsInt {
+		if ai < 0 {
+			return ai, int64(bn.(uint64)), reflect.Int64, nil
+		}
+		return uint64(ai), bn, reflect.Uint64, nil
+	}
+	if bIsInt && bi < 0 {
+		return int64(an.(uint64)), bi, reflect.Int64, nil
+	}
+	return an, uint64(bi), reflect.Uint64, nil
+}
+
+func toFloat64Kind(v interface{}, kind reflect.Kind) float64 {
+	switch kind {
+	case reflect.Int64:
+		return float64(v.(int64))
+	case reflect.Uint64:
+		return float64(v.(uint64))
+	default:
+		return v.(float64)
+	}
+}
+
+func typedArith(
+	intOp func(a, b int64) (interface{}, error),
+	uintOp func(a, b uint64) (interface{}, error),
+	floatOp func(a, b float64) (interface{}, error),
+) func(a, b interface{}) (interface{}, error) {
+	return func(a, b interface{}) (interface{}, error) {
+		av, bv, kind, err := promoteNumeric(a, b)
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case reflect.Int64:
+			return intOp(av.(int64), bv.(int64))
+		case reflect.Uint64:
+			return uintOp(av.(uint64), bv.(uint64))
+		default:
+			return floatOp(av.(float64), bv.(float64))
+		}
+	}
+}
+
+func typedDivide(o *typedArithmeticOptions) func(a, b interface{}) (interface{}, error) {
+	return func(a, b interface{}) (interface{}, error) {
+		av, bv, kind, err := promoteNumeric(a, b)
+		if err != nil {
+			return nil, err
+		}
+		if kind != reflect.Float64 && o.division == realDivision {
+			av, bv, kind = toFloat64Kind(av, kind), toFloat64Kind(bv, kind), reflect.Float64
+		}
+		switch kind {
+		case reflect.Int64:
+			bi := bv.(int64)
+			if bi == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return av.(int64) / bi, nil
+		case reflect.Uint64:
+			bu := bv.(uint64)
+			if bu == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return av.(uint64) / bu, nil
+		default:
+			return av.(float64) / bv.(float64), nil
+		}
+	}
+}
+
+func typedCompare(judge func(int) bool) func(a, b interface{}) (interface{}, error) {
+	return func(a, b interface{}) (interface{}, error) {
+		c, err := compareNumeric(a, b)
+		if err != nil {
+			return nil, err
+		}
+		return judge(c), nil
+	}
+}
+
+// compareNumeric compares a and b numerically regardless of their concrete int/uint/float
+// kind, following the same promotion rules as promoteNumeric, so int(3) > int32(2) and
+// uint(3) > float32(2.5) both work instead of erroring on a kind mismatch.
+func compareNumeric(a, b interface{}) (int, error) {
+	av, bv, kind, err := promoteNumeric(a, b)
+	if err != nil {
+		return 0, err
+	}
+	switch kind {
+	case reflect.Int64:
+		ai, bi := av.(int64), bv.(int64)
+		switch {
+		case ai < bi:
+			return -1, nil
+		case ai > bi:
+			return 1, nil
+		default:
+			return 0, nil
+		}
+	case reflect.Uint64:
+		au, bu := av.(uint64), bv.(uint64)
+		switch {
+		case au < bu:
+			return -1, nil
+		case au > bu:
+			return 1, nil
+		default:
+			return 0, nil
+		}
+	default:
+		af, bf := av.(float64), bv.(float64)
+		switch {
+		case af < bf:
+			return -1, nil
+		case af > bf:
+			return 1, nil
+		default:
+			return 0, nil
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package gval
+
+import "testing"
+
+func TestTypedArithmeticKeepsIntegerIdentity(t *testing.T) {
+	parameter := map[string]interface{}{
+		"i": int64(7),
+		"j": int64(2),
+		"u": uint64(7),
+		"v": uint64(2),
+	}
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{"i + j", int64(9)},
+		{"i - j", int64(5)},
+		{"i * j", int64(14)},
+		{"i % j", int64(1)},
+		{"i / j", int64(3)},
+		{"u + v", uint64(9)},
+		{"u / v", uint64(3)},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.expr, parameter, TypedArithmetic())
+		if err != nil {
+			t.Fatalf("Evaluate(%q): %s", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Errorf("Evaluate(%q) = %v (%T), want %v (%T)", tt.expr, got, got, tt.want, tt.want)
+		}
+	}
+}
+
+func TestTypedArithmeticFloatPromotion(t *testing.T) {
+	parameter := map[string]interface{}{"i": int64(3), "f": 0.5}
+	got, err := Evaluate("i + f", parameter, TypedArithmetic())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 3.5 {
+		t.Errorf("i + f = %v, want 3.5 (float64)", got)
+	}
+}
+
+func TestTypedArithmeticSignedUnsignedPromotion(t *testing.T) {
+	tests := []struct {
+		parameter map[string]interface{}
+		expr      string
+		want      interface{}
+	}{
+		// a non-negative int64 mixed with uint64 promotes to uint64
+		{map[string]interface{}{"i": int64(3), "u": uint64(2)}, "i + u", uint64(5)},
+		// a negative int64 mixed with uint64 promotes to int64
+		{map[string]interface{}{"i": int64(-3), "u": uint64(2)}, "i + u", int64(-1)},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.expr, tt.parameter, TypedArithmetic())
+		if err != nil {
+			t.Fatalf("Evaluate(%q): %s", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Errorf("Evaluate(%q) = %v (%T), want %v (%T)", tt.expr, got, got, tt.want, tt.want)
+		}
+	}
+}
+
+func TestTypedArithmeticRealDivision(t *testing.T) {
+	parameter := map[string]interface{}{"i": int64(7), "j": int64(2)}
+	got, err := Evaluate("i / j", parameter, TypedArithmetic(WithRealDivision()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 3.5 {
+		t.Errorf("i / j with WithRealDivision = %v, want 3.5", got)
+	}
+}
+
+func TestTypedArithmeticDivideByZero(t *testing.T) {
+	parameter := map[string]interface{}{"i": int64(1), "z": int64(0)}
+	if _, err := Evaluate("i / z", parameter, TypedArithmetic()); err == nil {
+		t.Fatal("expected a division by zero error")
+	}
+}
+
+func TestCompareNumericMixedKinds(t *testing.T) {
+	c, err := compareNumeric(int32(3), float32(2.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c <= 0 {
+		t.Errorf("compareNumeric(3, 2.5) = %d, want > 0", c)
+	}
+}
@@ -0,0 +1,487 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Gigaclank/gval/ast"
+)
+
+// opCode is a single bytecode instruction understood by Program.Run.
+type opCode byte
+
+const (
+	opConst opCode = iota
+	opLoad
+	opNeg
+	opNot
+	opBitNot
+	opToBool
+	opAdd
+	opSub
+	opMul
+	opDiv
+	opMod
+	opPow
+	opLt
+	opLte
+	opGt
+	opGte
+	opEq
+	opNeq
+	opIn
+	opRegexMatch
+	opRegexNotMatch
+	opBitAnd
+	opBitOr
+	opBitXor
+	opShl
+	opShr
+	opJump
+	opJumpIfFalse
+	opCoalesce
+	opIndex
+	opCall
+	opMakeArray
+	opMakeObject
+	opReturn
+)
+
+// binaryOps maps the binary opcodes onto the operator astApplyBinary expects; control
+// flow opcodes (opJump, opJumpIfFalse, ...) and everything with its own stack shape
+// (opCall, opIndex, opMakeArray, opMakeObject) are handled separately in Program.Run.
+var binaryOps = map[opCode]string{
+	opAdd: "+", opSub: "-", opMul: "*", opDiv: "/", opMod: "%", opPow: "**",
+	opLt: "<", opLte: "<=", opGt: ">", opGte: ">=", opEq: "==", opNeq: "!=",
+	opIn: "in", opRegexMatch: "=~", opRegexNotMatch: "!~",
+	opBitAnd: "&", opBitOr: "|", opBitXor: "^", opShl: "<<", opShr: ">>",
+}
+
+type instruction struct {
+	op  opCode
+	arg int
+}
+
+type callInfo struct {
+	name string
+	argc int
+}
+
+// Program is expression compiled by Compile into a flat slice of opcodes over an operand
+// stack, together with the constant pool and identifier table resolved at compile time.
+// Unlike the closure tree NewEvaluable produces, a Program does not re-walk the parsed
+// expression on every Run, which matters when the same expression is evaluated millions of
+// times (rules engines, filters over large record streams).
+type Program struct {
+	lang    Language
+	code    []instruction
+	consts  []interface{}
+	idents  []string
+	calls   []callInfo
+	objKeys [][]string
+}
+
+var vmStackPool = sync.Pool{
+	New: func() interface{} {
+		s := make([]interface{}, 0, 16)
+		return &s
+	},
+}
+
+// Compile parses expression with l.Parse and emits a Program whose opcodes reimplement the
+// operators of Full() plus any Function l itself registers, the same builtins
+// l.EvaluateAST supports - see l.Parse and l.EvaluateAST for how closely that matches
+// Full()'s own grammar and evaluator. Compile pays the parsing and opcode-emission cost
+// once; Run pays only for executing the opcodes. Compile(expression) is shorthand for
+// Full().Compile(expression).
+func (l Language) Compile(expression string) (*Program, error) {
+	node, err := l.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	c := &compiler{
+		constIndex: map[interface{}]int{},
+		identIndex: map[string]int{},
+	}
+	if err := c.compile(node); err != nil {
+		return nil, err
+	}
+	c.emit(opReturn, 0)
+	return &Program{lang: l, code: c.code, consts: c.consts, idents: c.idents, calls: c.calls, objKeys: c.objKeys}, nil
+}
+
+// Compile is shorthand for Full().Compile(expression).
+func Compile(expression string) (*Program, error) {
+	return full.Compile(expression)
+}
+
+// Run executes the program's opcodes against parameter and returns the result, the same
+// value the compiling Language's EvaluateAST returns for the parsed expression; see Compile
+// for how closely that matches Evaluate.
+func (p *Program) Run(ctx context.Context, parameter interface{}) (interface{}, error) {
+	stackPtr := vmStackPool.Get().(*[]interface{})
+	stack := (*stackPtr)[:0]
+	defer func() {
+		*stackPtr = stack[:0]
+		vmStackPool.Put(stackPtr)
+	}()
+
+	pc := 0
+	for pc < len(p.code) {
+		in := p.code[pc]
+
+		if op, ok := binaryOps[in.op]; ok {
+			n := len(stack)
+			a, b := stack[n-2], stack[n-1]
+			v, err := astApplyBinary(op, a, b)
+			if err != nil {
+				return nil, err
+			}
+			stack = stack[:n-2]
+			stack = append(stack, v)
+			pc++
+			continue
+		}
+
+		switch in.op {
+		case opConst:
+			stack = append(stack, p.consts[in.arg])
+			pc++
+
+		case opLoad:
+			v, err := astLookup(parameter, p.idents[in.arg])
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, v)
+			pc++
+
+		case opNeg:
+			top := len(stack) - 1
+			f, ok := convertToFloat(stack[top])
+			if !ok {
+				return nil, fmt.Errorf("unexpected %v(%T) expected number", stack[top], stack[top])
+			}
+			stack[top] = -f
+			pc++
+
+		case opNot:
+			top := len(stack) - 1
+			b, ok := convertToBool(stack[top])
+			if !ok {
+				return nil, fmt.Errorf("unexpected %T expected bool", stack[top])
+			}
+			stack[top] = !b
+			pc++
+
+		case opBitNot:
+			top := len(stack) - 1
+			f, ok := convertToFloat(stack[top])
+			if !ok {
+				return nil, fmt.Errorf("unexpected %T expected number", stack[top])
+			}
+			stack[top] = float64(^int64(f))
+			pc++
+
+		case opToBool:
+			top := len(stack) - 1
+			b, ok := convertToBool(stack[top])
+			if !ok {
+				return nil, fmt.Errorf("unexpected %T expected bool", stack[top])
+			}
+			stack[top] = b
+			pc++
+
+		case opJump:
+			pc = in.arg
+
+		case opJumpIfFalse:
+			top := len(stack) - 1
+			b := stack[top].(bool)
+			stack = stack[:top]
+			if !b {
+				pc = in.arg
+			} else {
+				pc++
+			}
+
+		case opCoalesce:
+			top := len(stack) - 1
+			v := stack[top]
+			if v == false || v == nil {
+				stack = stack[:top]
+				pc++
+			} else {
+				pc = in.arg
+			}
+
+		case opIndex:
+			n := len(stack)
+			recv, key := stack[n-2], stack[n-1]
+			v, err := astIndex(recv, key)
+			if err != nil {
+				return nil, err
+			}
+			stack = stack[:n-2]
+			stack = append(stack, v)
+			pc++
+
+		case opCall:
+			info := p.calls[in.arg]
+			n := len(stack)
+			args := append([]interface{}(nil), stack[n-info.argc:]...)
+			var v interface{}
+			var err error
+			if info.name == "date" {
+				if len(args) != 1 {
+					return nil, fmt.Errorf("date() expects exactly one string argument")
+				}
+				s, ok := args[0].(string)
+				if !ok {
+					return nil, fmt.Errorf("date() expects exactly one string argument")
+				}
+				v, err = astParseDate(s)
+			} else {
+				v, err = astCallFallback(p.lang, info.name, args)
+			}
+			if err != nil {
+				return nil, err
+			}
+			stack = stack[:n-info.argc]
+			stack = append(stack, v)
+			pc++
+
+		case opMakeArray:
+			n := len(stack)
+			arr := append([]interface{}(nil), stack[n-in.arg:]...)
+			stack = stack[:n-in.arg]
+			stack = append(stack, arr)
+			pc++
+
+		case opMakeObject:
+			keys := p.objKeys[in.arg]
+			n := len(stack)
+			vals := stack[n-len(keys):]
+			obj := make(map[string]interface{}, len(keys))
+			for i, k := range keys {
+				obj[k] = vals[i]
+			}
+			stack = stack[:n-len(keys)]
+			stack = append(stack, obj)
+			pc++
+
+		case opReturn:
+			if len(stack) == 0 {
+				return nil, nil
+			}
+			return stack[len(stack)-1], nil
+
+		default:
+			return nil, fmt.Errorf("unknown opcode %d", in.op)
+		}
+	}
+	return nil, fmt.Errorf("program ran off the end without a return")
+}
+
+type compiler struct {
+	code       []instruction
+	consts     []interface{}
+	constIndex map[interface{}]int
+	idents     []string
+	identIndex map[string]int
+	calls      []callInfo
+	objKeys    [][]string
+}
+
+func (c *compiler) emit(op opCode, arg int) int {
+	c.code = append(c.code, instruction{op: op, arg: arg})
+	return len(c.code) - 1
+}
+
+func (c *compiler) patch(pos, target int) {
+	c.code[pos].arg = target
+}
+
+func (c *compiler) constant(v interface{}) int {
+	if i, ok := c.constIndex[v]; ok {
+		return i
+	}
+	i := len(c.consts)
+	c.consts = append(c.consts, v)
+	c.constIndex[v] = i
+	return i
+}
+
+func (c *compiler) ident(name string) int {
+	if i, ok := c.identIndex[name]; ok {
+		return i
+	}
+	i := len(c.idents)
+	c.idents = append(c.idents, name)
+	c.identIndex[name] = i
+	return i
+}
+
+var unaryOps = map[string]opCode{"-": opNeg, "!": opNot, "~": opBitNot}
+
+func (c *compiler) compile(node ast.Node) error {
+	switch n := node.(type) {
+	case *ast.Literal:
+		c.emit(opConst, c.constant(n.Value))
+		return nil
+
+	case *ast.Ident:
+		c.emit(opLoad, c.ident(n.Name))
+		return nil
+
+	case *ast.PrefixOp:
+		op, ok := unaryOps[n.Op]
+		if !ok {
+			return fmt.Errorf("unknown prefix operator %s", n.Op)
+		}
+		if err := c.compile(n.Operand); err != nil {
+			return err
+		}
+		c.emit(op, 0)
+		return nil
+
+	case *ast.BinaryOp:
+		return c.compileBinary(n)
+
+	case *ast.Conditional:
+		if err := c.compile(n.CondExpr); err != nil {
+			return err
+		}
+		c.emit(opToBool, 0)
+		jumpToFalse := c.emit(opJumpIfFalse, 0)
+		if err := c.compile(n.TrueExpr); err != nil {
+			return err
+		}
+		jumpToEnd := c.emit(opJump, 0)
+		c.patch(jumpToFalse, len(c.code))
+		if err := c.compile(n.FalseExpr); err != nil {
+			return err
+		}
+		c.patch(jumpToEnd, len(c.code))
+		return nil
+
+	case *ast.Call:
+		for _, a := range n.Args {
+			if err := c.compile(a); err != nil {
+				return err
+			}
+		}
+		c.calls = append(c.calls, callInfo{name: n.Name, argc: len(n.Args)})
+		c.emit(opCall, len(c.calls)-1)
+		return nil
+
+	case *ast.Index:
+		if err := c.compile(n.Receiver); err != nil {
+			return err
+		}
+		if err := c.compile(n.Key); err != nil {
+			return err
+		}
+		c.emit(opIndex, 0)
+		return nil
+
+	case *ast.JSONArray:
+		for _, e := range n.Elements {
+			if err := c.compile(e); err != nil {
+				return err
+			}
+		}
+		c.emit(opMakeArray, len(n.Elements))
+		return nil
+
+	case *ast.JSONObject:
+		keys := make([]string, len(n.Entries))
+		for i, e := range n.Entries {
+			if err := c.compile(e.Value); err != nil {
+				return err
+			}
+			keys[i] = e.Key
+		}
+		c.objKeys = append(c.objKeys, keys)
+		c.emit(opMakeObject, len(c.objKeys)-1)
+		return nil
+	}
+	return fmt.Errorf("unsupported node %T", node)
+}
+
+// compileBinary emits && and || as jumps so the right operand is skipped once the result
+// is already decided, the same short-circuit evaluation evalASTBinary does; every other
+// operator just pushes both operands and applies a single binary opcode.
+func (c *compiler) compileBinary(n *ast.BinaryOp) error {
+	switch n.Op {
+	case "&&":
+		return c.compileShortCircuit(n, false)
+	case "||":
+		return c.compileShortCircuit(n, true)
+	case "??":
+		return c.compileCoalesce(n)
+	}
+
+	if err := c.compile(n.Left); err != nil {
+		return err
+	}
+	if err := c.compile(n.Right); err != nil {
+		return err
+	}
+	opcode, ok := opcodeForOperator[n.Op]
+	if !ok {
+		return fmt.Errorf("unknown operator %s", n.Op)
+	}
+	c.emit(opcode, 0)
+	return nil
+}
+
+// compileShortCircuit compiles a && b (stopOn false) or a || b (stopOn true): once the
+// bool-converted left side already equals stopOn, the whole expression is decided and the
+// right side is never evaluated.
+func (c *compiler) compileShortCircuit(n *ast.BinaryOp, stopOn bool) error {
+	if err := c.compile(n.Left); err != nil {
+		return err
+	}
+	c.emit(opToBool, 0)
+	if stopOn {
+		// opJumpIfFalse jumps when the top of stack is false; negate first so it fires
+		// exactly when the left side already equals stopOn (true).
+		c.emit(opNot, 0)
+	}
+	decided := c.emit(opJumpIfFalse, 0)
+
+	if err := c.compile(n.Right); err != nil {
+		return err
+	}
+	c.emit(opToBool, 0)
+	end := c.emit(opJump, 0)
+
+	c.patch(decided, len(c.code))
+	c.emit(opConst, c.constant(stopOn))
+
+	c.patch(end, len(c.code))
+	return nil
+}
+
+// compileCoalesce compiles a ?? b: if a is not nil and not false, it is the result and b
+// is never evaluated; otherwise the result is b.
+func (c *compiler) compileCoalesce(n *ast.BinaryOp) error {
+	if err := c.compile(n.Left); err != nil {
+		return err
+	}
+	jump := c.emit(opCoalesce, 0)
+	if err := c.compile(n.Right); err != nil {
+		return err
+	}
+	c.patch(jump, len(c.code))
+	return nil
+}
+
+var opcodeForOperator = map[string]opCode{
+	"+": opAdd, "-": opSub, "*": opMul, "/": opDiv, "%": opMod, "**": opPow,
+	"<": opLt, "<=": opLte, ">": opGt, ">=": opGte, "==": opEq, "!=": opNeq,
+	"in": opIn, "=~": opRegexMatch, "!~": opRegexNotMatch,
+	"&": opBitAnd, "|": opBitOr, "^": opBitXor, "<<": opShl, ">>": opShr,
+}
@@ -0,0 +1,112 @@
+package gval
+
+import (
+	"context"
+	"testing"
+)
+
+func runProgram(t *testing.T, expr string, parameter interface{}) interface{} {
+	t.Helper()
+	prog, err := Compile(expr)
+	if err != nil {
+		t.Fatalf("Compile(%q): %s", expr, err)
+	}
+	got, err := prog.Run(context.Background(), parameter)
+	if err != nil {
+		t.Fatalf("Run(%q): %s", expr, err)
+	}
+	return got
+}
+
+func TestProgramRunMatchesEvaluate(t *testing.T) {
+	tests := []struct {
+		expr      string
+		parameter interface{}
+	}{
+		{"1 + 2 * 3", nil},
+		{"(1 + 2) * 3", nil},
+		{"a.b", map[string]interface{}{"a": map[string]interface{}{"b": 42.0}}},
+		{"a[1]", map[string]interface{}{"a": []interface{}{1.0, 2.0, 3.0}}},
+		{`{"a": 1}.a`, nil},
+	}
+	for _, tt := range tests {
+		got := runProgram(t, tt.expr, tt.parameter)
+		want, err := Evaluate(tt.expr, tt.parameter)
+		if err != nil {
+			t.Fatalf("Evaluate(%q): %s", tt.expr, err)
+		}
+		if got != want {
+			t.Errorf("Run(%q) = %v, want %v", tt.expr, got, want)
+		}
+	}
+}
+
+// TestProgramRunShortCircuitsAndOr relies on loading the unknown parameter missing erroring
+// if it is ever evaluated, so a passing test confirms the VM's jump-based short circuit
+// actually skips the right operand instead of merely returning the right answer despite
+// evaluating it.
+func TestProgramRunShortCircuitsAndOr(t *testing.T) {
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{"false && missing", false},
+		{"true || missing", true},
+	}
+	for _, tt := range tests {
+		prog, err := Compile(tt.expr)
+		if err != nil {
+			t.Fatalf("Compile(%q): %s", tt.expr, err)
+		}
+		got, err := prog.Run(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("Run(%q) should have short-circuited before loading missing, got error: %s", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Errorf("Run(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestProgramRunCoalesce(t *testing.T) {
+	tests := []struct {
+		parameter interface{}
+		want      interface{}
+	}{
+		{map[string]interface{}{"a": nil, "b": 2.0}, 2.0},
+		{map[string]interface{}{"a": 1.0, "b": 2.0}, 1.0},
+	}
+	for _, tt := range tests {
+		got := runProgram(t, "a ?? b", tt.parameter)
+		if got != tt.want {
+			t.Errorf("a ?? b = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestProgramRunArrayEquality(t *testing.T) {
+	got := runProgram(t, "[1,2,3] == 2", nil)
+	if got != true {
+		t.Errorf("[1,2,3] == 2 = %v, want true", got)
+	}
+}
+
+func TestProgramRunCallsCustomFunction(t *testing.T) {
+	l := NewLanguage(
+		Function("double", func(arguments ...interface{}) (interface{}, error) {
+			return 2 * arguments[0].(float64), nil
+		}),
+		Full(),
+	)
+	prog, err := l.Compile("double(21)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := prog.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 42.0 {
+		t.Errorf("double(21) = %v, want 42", got)
+	}
+}